@@ -0,0 +1,102 @@
+// Package conversation persists per-user, multi-turn chat history in Redis
+// so a conversation can span multiple HTTP requests.
+package conversation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shayyz-code/raccoon-sku/backend/llama"
+)
+
+// Store persists conversation history as a Redis list of JSON-encoded
+// llama.Message values under key "conv:{user}:{id}", capped at maxLen
+// entries (oldest trimmed first) and expiring after ttl of inactivity.
+type Store struct {
+	client *redis.Client
+	ttl    time.Duration
+	maxLen int64
+}
+
+// NewStore returns a Store backed by client. ttl is refreshed on every
+// Append and Replace; maxLen bounds how many messages are kept.
+func NewStore(client *redis.Client, ttl time.Duration, maxLen int) *Store {
+	return &Store{client: client, ttl: ttl, maxLen: int64(maxLen)}
+}
+
+func conversationKey(userID, conversationID string) string {
+	return fmt.Sprintf("conv:%s:%s", userID, conversationID)
+}
+
+// Append pushes msg onto the conversation, trims it to maxLen and refreshes
+// its TTL.
+func (s *Store) Append(ctx context.Context, userID, conversationID string, msg llama.Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	key := conversationKey(userID, conversationID)
+	if err := s.client.RPush(ctx, key, body).Err(); err != nil {
+		return fmt.Errorf("failed to append message: %w", err)
+	}
+	if err := s.client.LTrim(ctx, key, -s.maxLen, -1).Err(); err != nil {
+		return fmt.Errorf("failed to trim conversation: %w", err)
+	}
+	if err := s.client.Expire(ctx, key, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to refresh conversation TTL: %w", err)
+	}
+	return nil
+}
+
+// Messages returns every message currently stored for the conversation,
+// oldest first.
+func (s *Store) Messages(ctx context.Context, userID, conversationID string) ([]llama.Message, error) {
+	raw, err := s.client.LRange(ctx, conversationKey(userID, conversationID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conversation: %w", err)
+	}
+
+	messages := make([]llama.Message, 0, len(raw))
+	for _, r := range raw {
+		var msg llama.Message
+		if err := json.Unmarshal([]byte(r), &msg); err != nil {
+			return nil, fmt.Errorf("failed to decode stored message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// Delete removes all stored history for the conversation.
+func (s *Store) Delete(ctx context.Context, userID, conversationID string) error {
+	if err := s.client.Del(ctx, conversationKey(userID, conversationID)).Err(); err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	return nil
+}
+
+// Replace overwrites the whole stored history with messages, used by
+// Summarize to splice a summary message in for the messages it replaced.
+func (s *Store) Replace(ctx context.Context, userID, conversationID string, messages []llama.Message) error {
+	key := conversationKey(userID, conversationID)
+
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, key)
+	for _, msg := range messages {
+		body, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message: %w", err)
+		}
+		pipe.RPush(ctx, key, body)
+	}
+	pipe.Expire(ctx, key, s.ttl)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to replace conversation: %w", err)
+	}
+	return nil
+}
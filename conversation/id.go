@@ -0,0 +1,57 @@
+package conversation
+
+import (
+	"crypto/rand"
+	"time"
+)
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewID returns a ULID-style identifier: a 48-bit millisecond timestamp
+// followed by 80 bits of randomness, Crockford base32 encoded so IDs sort
+// lexicographically by creation time. It's a small hand-rolled encoder
+// rather than a pulled-in ULID dependency, since this snapshot has no
+// go.mod to vendor one into; the sortable-by-time property is all callers
+// here rely on.
+func NewID() string {
+	var buf [16]byte
+
+	ms := uint64(time.Now().UnixMilli())
+	buf[0] = byte(ms >> 40)
+	buf[1] = byte(ms >> 32)
+	buf[2] = byte(ms >> 24)
+	buf[3] = byte(ms >> 16)
+	buf[4] = byte(ms >> 8)
+	buf[5] = byte(ms)
+
+	// A failed crypto/rand read is effectively unrecoverable; falling back
+	// to the zeroed buffer just means a less-random (but still unique
+	// enough, given the timestamp prefix) suffix rather than a panic.
+	rand.Read(buf[6:])
+
+	return encodeCrockford(buf)
+}
+
+func encodeCrockford(buf [16]byte) string {
+	var out [26]byte
+
+	var bits uint64
+	var bitCount uint
+	idx := 0
+
+	for _, b := range buf {
+		bits = bits<<8 | uint64(b)
+		bitCount += 8
+		for bitCount >= 5 {
+			bitCount -= 5
+			out[idx] = crockfordAlphabet[(bits>>bitCount)&0x1F]
+			idx++
+		}
+	}
+	if bitCount > 0 {
+		out[idx] = crockfordAlphabet[(bits<<(5-bitCount))&0x1F]
+		idx++
+	}
+
+	return string(out[:idx])
+}
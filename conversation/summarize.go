@@ -0,0 +1,60 @@
+package conversation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shayyz-code/raccoon-sku/backend/llama"
+)
+
+// summarizeSystemPrompt is the instruction sent to the provider when
+// condensing older turns; it's deliberately generic since any registered
+// Provider might be asked to run it.
+const summarizeSystemPrompt = "Summarize the following conversation concisely, preserving any facts, decisions, or open questions that matter for future turns."
+
+// EstimateTokens is a cheap token estimate used to decide when a
+// conversation needs summarizing. We don't have a real tokenizer handy for
+// every provider, so ~4 characters per token is the usual rule of thumb for
+// English text.
+func EstimateTokens(messages []llama.Message) int {
+	chars := 0
+	for _, m := range messages {
+		chars += len(m.Content)
+	}
+	return chars / 4
+}
+
+// Summarize returns messages unchanged if it's within tokenBudget or too
+// short to usefully compress. Otherwise it asks provider to summarize every
+// message except the most recent keepRecent, and returns a shorter list
+// with those older turns replaced by a single system message holding the
+// summary.
+func Summarize(provider llama.Provider, messages []llama.Message, tokenBudget, keepRecent int) ([]llama.Message, error) {
+	if EstimateTokens(messages) <= tokenBudget || len(messages) <= keepRecent {
+		return messages, nil
+	}
+
+	cut := len(messages) - keepRecent
+
+	var transcript strings.Builder
+	for _, m := range messages[:cut] {
+		fmt.Fprintf(&transcript, "%s: %s\n", m.Role, m.Content)
+	}
+
+	summary, err := provider.Ask(llama.Prompt{
+		System: summarizeSystemPrompt,
+		User:   transcript.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize conversation: %w", err)
+	}
+
+	summarized := make([]llama.Message, 0, keepRecent+1)
+	summarized = append(summarized, llama.Message{
+		Role:    "system",
+		Content: "Summary of earlier conversation: " + summary,
+	})
+	summarized = append(summarized, messages[cut:]...)
+
+	return summarized, nil
+}
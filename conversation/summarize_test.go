@@ -0,0 +1,78 @@
+package conversation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/shayyz-code/raccoon-sku/backend/llama"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	messages := []llama.Message{
+		{Role: "user", Content: "12345678"}, // 8 chars
+		{Role: "assistant", Content: "1234"}, // 4 chars
+	}
+
+	got := EstimateTokens(messages)
+	want := 3 // (8 + 4) / 4
+	if got != want {
+		t.Errorf("EstimateTokens() = %d, want %d", got, want)
+	}
+}
+
+func TestSummarize_UnderBudgetReturnsUnchanged(t *testing.T) {
+	messages := []llama.Message{
+		{Role: "user", Content: "hi"},
+		{Role: "assistant", Content: "hello"},
+	}
+
+	got, err := Summarize(fakeProvider{}, messages, 1000, 1)
+	if err != nil {
+		t.Fatalf("Summarize() returned an unexpected error: %v", err)
+	}
+	if len(got) != len(messages) {
+		t.Errorf("Summarize() returned %d messages, want %d unchanged", len(got), len(messages))
+	}
+}
+
+// fakeProvider is a minimal llama.Provider stand-in so Summarize can be
+// tested without hitting a real API.
+type fakeProvider struct {
+	reply string
+}
+
+func (f fakeProvider) Name() string     { return "fake" }
+func (f fakeProvider) Models() []string { return nil }
+
+func (f fakeProvider) Ask(prompt llama.Prompt) (string, error) {
+	return f.reply, nil
+}
+
+func (f fakeProvider) AskStream(ctx context.Context, prompt llama.Prompt) (<-chan llama.Token, <-chan error) {
+	tokens := make(chan llama.Token)
+	errs := make(chan error)
+	close(tokens)
+	close(errs)
+	return tokens, errs
+}
+
+func TestSummarize_OverBudgetCallsProvider(t *testing.T) {
+	var messages []llama.Message
+	for i := 0; i < 20; i++ {
+		messages = append(messages, llama.Message{Role: "user", Content: fmt.Sprintf("message number %d with some padding text", i)})
+	}
+
+	provider := fakeProvider{reply: "condensed summary"}
+	got, err := Summarize(provider, messages, 10, 2)
+	if err != nil {
+		t.Fatalf("Summarize() returned an unexpected error: %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("Summarize() returned %d messages, want 3 (1 summary + 2 kept)", len(got))
+	}
+	if got[0].Role != "system" {
+		t.Errorf("Summarize() first message role = %q, want %q", got[0].Role, "system")
+	}
+}
@@ -0,0 +1,54 @@
+package jwt
+
+import (
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// HSVerifier verifies tokens signed with a single shared HS256 secret,
+// the scheme this package has always used for GenerateJWT.
+type HSVerifier struct {
+	Secret []byte
+}
+
+// NewHSVerifier returns an HSVerifier that checks signatures against secret.
+func NewHSVerifier(secret []byte) HSVerifier {
+	return HSVerifier{Secret: secret}
+}
+
+// Verify implements Verifier. It strictly requires HS256 in the keyfunc
+// rather than trusting whatever alg the token claims, so a token forged
+// with e.g. "none" or signed with an asymmetric key can't be accepted just
+// because Secret happens to also be a valid public key elsewhere.
+func (v HSVerifier) Verify(tokenStr string) (string, error) {
+	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
+		if t.Method.Alg() != "HS256" {
+			return nil, fmt.Errorf("unexpected signing method: %s", t.Method.Alg())
+		}
+		return v.Secret, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if !token.Valid {
+		return "", ErrTokenInvalid
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", fmt.Errorf("unexpected claims type %T", token.Claims)
+	}
+
+	userValue, exists := claims["user"]
+	if !exists || userValue == nil {
+		return "", fmt.Errorf("user claim is missing or nil")
+	}
+
+	userID, ok := userValue.(string)
+	if !ok {
+		return "", fmt.Errorf("user claim is not a string")
+	}
+
+	return userID, nil
+}
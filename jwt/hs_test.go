@@ -0,0 +1,47 @@
+package jwt
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TestHSVerifier_RejectsNoneAlgorithm guards against the classic JWT
+// "alg: none" forgery: a token claiming it's unsigned (or signed with a
+// method other than HS256) must never be accepted just because its claims
+// look valid.
+func TestHSVerifier_RejectsNoneAlgorithm(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+		"user": "attacker",
+	})
+
+	tokenStr, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("failed to build unsigned test token: %v", err)
+	}
+
+	v := NewHSVerifier([]byte("test-secret"))
+	if _, err := v.Verify(tokenStr); err == nil {
+		t.Fatal("Verify() accepted an unsigned \"none\"-algorithm token, want error")
+	}
+}
+
+func TestHSVerifier_AcceptsValidToken(t *testing.T) {
+	secret := []byte("test-secret")
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user": "user-123",
+	})
+	tokenStr, err := token.SignedString(secret)
+	if err != nil {
+		t.Fatalf("failed to sign test token: %v", err)
+	}
+
+	v := NewHSVerifier(secret)
+	userID, err := v.Verify(tokenStr)
+	if err != nil {
+		t.Fatalf("Verify() returned an unexpected error: %v", err)
+	}
+	if userID != "user-123" {
+		t.Errorf("Verify() = %q, want %q", userID, "user-123")
+	}
+}
@@ -1,9 +1,11 @@
+// Package jwt issues and verifies the bearer tokens /api routes require.
+// Verification goes through the Verifier interface (see verifier.go) so a
+// deployment can choose between a shared HS256 secret (HSVerifier) and a
+// remote OIDC provider (OIDCVerifier) via AUTH_MODE.
 package jwt
 
 import (
 	"errors"
-	"fmt"
-	"log"
 	"os"
 	"time"
 
@@ -25,41 +27,7 @@ func GenerateJWT(userID string) (string, error) {
 		"exp":  time.Now().Add(365 * 24 * time.Hour).Unix(),
 	}
 
-	fmt.Printf("Parsed claims: %+v\n", claims)
-
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(secret)
 }
 
-// ParseJWT validates and parses the JWT, returning the userID
-func ParseJWT(tokenStr string) (string, error) {
-	secret := []byte(os.Getenv("JWT_SECRET"))
-
-	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
-		return secret, nil
-	})
-	if err != nil || !token.Valid {
-		return "", err
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", err
-	}
-	log.Println("Parsed claims:", claims)
-
-
-	userValue, exists := claims["user"]
-	if !exists || userValue == nil {
-		return "", fmt.Errorf("user claim is missing or nil")
-	}
-
-	userID, ok := userValue.(string)
-	if !ok {
-		return "", fmt.Errorf("user claim is not a string")
-	}
-
-	return userID, nil
-}
-
-
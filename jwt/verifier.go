@@ -0,0 +1,38 @@
+package jwt
+
+import (
+	"fmt"
+	"os"
+)
+
+// Verifier authenticates a bearer token and returns the user ID it
+// identifies. apiMiddleware talks to this interface rather than a concrete
+// scheme so a deployment can switch between a shared HS256 secret and a
+// remote OIDC provider by config alone.
+type Verifier interface {
+	Verify(tokenStr string) (string, error)
+}
+
+// Default returns the Verifier selected by AUTH_MODE ("hs" or "oidc"),
+// falling back to "hs" so existing deployments that don't set it keep
+// using the shared-secret scheme unchanged.
+func Default() (Verifier, error) {
+	mode := os.Getenv("AUTH_MODE")
+	if mode == "" {
+		mode = "hs"
+	}
+
+	switch mode {
+	case "hs":
+		return NewHSVerifier([]byte(os.Getenv("JWT_SECRET"))), nil
+	case "oidc":
+		issuer := os.Getenv("OIDC_ISSUER")
+		audience := os.Getenv("OIDC_AUDIENCE")
+		if issuer == "" || audience == "" {
+			return nil, fmt.Errorf("OIDC_ISSUER and OIDC_AUDIENCE must both be set when AUTH_MODE=oidc")
+		}
+		return NewOIDCVerifier(issuer, audience), nil
+	default:
+		return nil, fmt.Errorf("unknown AUTH_MODE %q", mode)
+	}
+}
@@ -0,0 +1,55 @@
+package limiter
+
+import (
+	"expvar"
+	"net/http"
+	"regexp"
+)
+
+// inFlightCurrent and inFlightWaiting are exported via expvar so operators
+// can watch them (e.g. scraped alongside the Go runtime's own expvar
+// metrics) and size MaxInFlight's capacity for a given deployment.
+var (
+	inFlightCurrent = expvar.NewInt("limiter_in_flight_current")
+	inFlightWaiting = expvar.NewInt("limiter_in_flight_waiting")
+)
+
+// MaxInFlight returns middleware that caps the number of requests being
+// handled concurrently to n, protecting the process from a burst of
+// concurrent Groq calls tying up file descriptors and memory. It has
+// nothing to do with RateLimiter's per-user daily counter; the two are
+// meant to be layered.
+//
+// Requests whose path matches longRunningRE bypass the semaphore entirely:
+// they're expected to hold a slot for minutes (e.g. a streaming completion)
+// and would otherwise starve short requests queued up behind them. Pass a
+// nil longRunningRE to have every request go through the semaphore.
+func MaxInFlight(n int, longRunningRE *regexp.Regexp) func(http.Handler) http.Handler {
+	sem := make(chan struct{}, n)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if longRunningRE != nil && longRunningRE.MatchString(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			inFlightWaiting.Add(1)
+			select {
+			case sem <- struct{}{}:
+				inFlightWaiting.Add(-1)
+			default:
+				inFlightWaiting.Add(-1)
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "Too many concurrent requests", http.StatusServiceUnavailable)
+				return
+			}
+			defer func() { <-sem }()
+
+			inFlightCurrent.Add(1)
+			defer inFlightCurrent.Add(-1)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -2,44 +2,40 @@ package limiter
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// RateLimiter is a single-rule sliding-window-log limiter, kept as a thin
+// adapter around MultiRateLimiter so existing callers of Allow(userID)
+// don't need to change. New call sites that need more than one rule, or
+// that want the X-RateLimit-* headers, should use NewMultiRateLimiter and
+// Middleware directly instead.
 type RateLimiter struct {
-	client  *redis.Client
-	limit   int           // requests
-	window  time.Duration // time window
+	multi *MultiRateLimiter
+	rule  Rule
 }
 
+// NewRateLimiter returns a RateLimiter allowing up to limit requests per
+// window, per key.
 func NewRateLimiter(client *redis.Client, limit int, window time.Duration) *RateLimiter {
+	rule := Rule{Name: "default", Limit: limit, Window: window}
 	return &RateLimiter{
-		client: client,
-		limit:  limit,
-		window: window,
+		multi: NewMultiRateLimiter(client, []Rule{rule}),
+		rule:  rule,
 	}
 }
 
+// Allow reports whether userID is still within its limit, atomically
+// recording the request if so. It used to be backed by a fixed-window
+// INCR+EXPIRE counter, which lets a caller burst up to 2x the limit across
+// a window boundary; it's now a sliding-window log under the hood, but the
+// signature is unchanged so existing callers keep working as-is.
 func (rl *RateLimiter) Allow(userID string) (bool, error) {
-	ctx := context.Background()
-	key := fmt.Sprintf("rate_limit:%s", userID)
-
-	// increment the counter
-	count, err := rl.client.Incr(ctx, key).Result()
+	decisions, err := rl.multi.Check(context.Background(), userID)
 	if err != nil {
 		return false, err
 	}
-
-	// set expiration if this is the first request
-	if count == 1 {
-		err := rl.client.Expire(ctx, key, rl.window).Err()
-		if err != nil {
-			return false, err
-		}
-	}
-
-	return count <= int64(rl.limit), nil
+	return decisions[0].Allowed, nil
 }
-
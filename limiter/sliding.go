@@ -0,0 +1,183 @@
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Rule is one named limit evaluated by MultiRateLimiter: at most Limit
+// requests per Window.
+type Rule struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// slidingWindowScript atomically evaluates a sliding-window log rate limit
+// for a single key: it drops entries older than the window, counts what's
+// left, and either admits the new request (recording it) or rejects it. It
+// also returns the score of the oldest surviving entry so the caller can
+// compute a precise reset time / Retry-After, something a plain INCR+EXPIRE
+// counter can't do without also permitting bursts of up to 2x the limit
+// around the window boundary.
+//
+// Each admitted request is recorded as its own sorted-set member, scored by
+// "now" but named "now:seq" (seq from a key-local counter) so two requests
+// landing in the same millisecond don't collapse into a single member -
+// using "now" alone as the member would make ZADD a no-op for the second
+// request and let ZCARD under-count concurrent callers past the limit.
+//
+// KEYS[1] = the sorted-set key
+// KEYS[2] = the per-key sequence counter
+// ARGV[1] = now (unix ms)
+// ARGV[2] = window (ms)
+// ARGV[3] = limit
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local seqKey = KEYS[2]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call("ZREMRANGEBYSCORE", key, 0, now - window)
+
+local count = redis.call("ZCARD", key)
+if count < limit then
+	local seq = redis.call("INCR", seqKey)
+	redis.call("PEXPIRE", seqKey, window)
+	redis.call("ZADD", key, now, now .. ":" .. seq)
+	redis.call("PEXPIRE", key, window)
+	return {1, count + 1, 0}
+end
+
+local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+local oldestScore = 0
+if #oldest == 2 then
+	oldestScore = tonumber(oldest[2])
+end
+return {0, count, oldestScore}
+`)
+
+// Decision is the outcome of evaluating one Rule against its current state.
+type Decision struct {
+	Rule      Rule
+	Allowed   bool
+	Remaining int
+	// ResetAt is when the oldest entry in the current window expires, i.e.
+	// when capacity next frees up.
+	ResetAt time.Time
+}
+
+// MultiRateLimiter evaluates a sequence of Rules, each backed by its own
+// sliding-window log, so a deployment can express e.g. "20 per minute AND
+// 200 per day" rather than a single fixed-window counter.
+type MultiRateLimiter struct {
+	client *redis.Client
+	rules  []Rule
+}
+
+// NewMultiRateLimiter returns a MultiRateLimiter evaluating rules in the
+// order given. Check stops at the first rule a key fails, so put the rule
+// you want to be the "reason" for a 429 first if that matters to callers.
+func NewMultiRateLimiter(client *redis.Client, rules []Rule) *MultiRateLimiter {
+	return &MultiRateLimiter{client: client, rules: rules}
+}
+
+// Check evaluates every rule for key in order, stopping at the first
+// rejection, and returns a Decision for each rule it evaluated.
+func (m *MultiRateLimiter) Check(ctx context.Context, key string) ([]Decision, error) {
+	decisions := make([]Decision, 0, len(m.rules))
+
+	for _, rule := range m.rules {
+		now := time.Now().UnixMilli()
+		windowMs := rule.Window.Milliseconds()
+
+		setKey := fmt.Sprintf("rate_limit:%s:%s", rule.Name, key)
+		res, err := slidingWindowScript.Run(ctx, m.client, []string{setKey, setKey + ":seq"}, now, windowMs, rule.Limit).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate rate limit rule %q: %w", rule.Name, err)
+		}
+
+		values, ok := res.([]interface{})
+		if !ok || len(values) != 3 {
+			return nil, fmt.Errorf("unexpected response from rate limit script for rule %q", rule.Name)
+		}
+
+		allowed := values[0].(int64) == 1
+		count := values[1].(int64)
+		oldestMs := values[2].(int64)
+
+		remaining := rule.Limit - int(count)
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		resetAt := time.Now().Add(rule.Window)
+		if oldestMs > 0 {
+			resetAt = time.UnixMilli(oldestMs).Add(rule.Window)
+		}
+
+		decisions = append(decisions, Decision{
+			Rule:      rule,
+			Allowed:   allowed,
+			Remaining: remaining,
+			ResetAt:   resetAt,
+		})
+
+		if !allowed {
+			break
+		}
+	}
+
+	return decisions, nil
+}
+
+// Middleware rate-limits requests by the key keyFunc extracts (e.g. the
+// authenticated user ID), evaluating every rule via ml.Check and setting
+// X-RateLimit-Limit/Remaining/Reset from the last (tightest, since Check
+// stops at the first failure) rule it evaluated on every response. Requests
+// with an empty key (keyFunc couldn't identify a caller) are passed
+// through unlimited.
+func Middleware(ml *MultiRateLimiter, keyFunc func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := keyFunc(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			decisions, err := ml.Check(r.Context(), key)
+			if err != nil {
+				http.Error(w, "Rate limit check failed", http.StatusInternalServerError)
+				return
+			}
+			if len(decisions) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tightest := decisions[len(decisions)-1]
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(tightest.Rule.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(tightest.ResetAt.Unix(), 10))
+
+			if !tightest.Allowed {
+				retryAfter := int(time.Until(tightest.ResetAt).Seconds())
+				if retryAfter < 1 {
+					retryAfter = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
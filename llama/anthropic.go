@@ -0,0 +1,263 @@
+package llama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shayyz-code/raccoon-sku/backend/metrics"
+)
+
+// AnthropicProvider talks to the Anthropic Messages API. Unlike the other
+// providers it authenticates via an "x-api-key" header plus a required
+// "anthropic-version" header rather than a Bearer token, and its system
+// prompt is a top-level request field rather than a message with role
+// "system".
+type AnthropicProvider struct{}
+
+const anthropicAPIVersion = "2023-06-01"
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	MaxTokens int                `json:"max_tokens"`
+	Stream    bool               `json:"stream"`
+}
+
+type anthropicResponse struct {
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+	Usage      anthropicUsage          `json:"usage"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// anthropicStreamEvent covers the subset of Anthropic's SSE event payloads
+// we care about: incremental text deltas. Other event types (message_start,
+// content_block_start, message_stop, ...) unmarshal into the same struct
+// and are simply ignored when Delta.Text is empty.
+type anthropicStreamEvent struct {
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+// anthropicMessages converts a Prompt's History plus its final user turn
+// into the anthropicMessage shape the API expects. System is sent
+// separately as its own request field, so it's deliberately left out here.
+func anthropicMessages(prompt Prompt) []anthropicMessage {
+	messages := make([]anthropicMessage, 0, len(prompt.History)+1)
+	for _, m := range prompt.History {
+		messages = append(messages, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+	messages = append(messages, anthropicMessage{Role: "user", Content: prompt.User})
+	return messages
+}
+
+// Name implements Provider.
+func (a AnthropicProvider) Name() string { return "anthropic" }
+
+// Models implements Provider.
+func (a AnthropicProvider) Models() []string {
+	return []string{"claude-opus-4-5", "claude-sonnet-4-5", "claude-haiku-4-5"}
+}
+
+func (a AnthropicProvider) config() (apiURL, apiKey, model string, err error) {
+	apiURL = os.Getenv("ANTHROPIC_API_URL")
+	if apiURL == "" {
+		apiURL = "https://api.anthropic.com/v1/messages"
+	}
+	apiKey = os.Getenv("ANTHROPIC_API_KEY")
+	model = os.Getenv("ANTHROPIC_MODEL")
+
+	if apiKey == "" || model == "" {
+		return "", "", "", fmt.Errorf("one or more Anthropic environment variables are not set: ANTHROPIC_API_KEY, ANTHROPIC_MODEL")
+	}
+	return apiURL, apiKey, model, nil
+}
+
+func (a AnthropicProvider) newRequest(ctx context.Context, apiURL, apiKey string, bodyBytes []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+	return req, nil
+}
+
+// Ask sends a prompt to the Anthropic API and returns the complete response.
+func (a AnthropicProvider) Ask(prompt Prompt) (string, error) {
+	apiURL, apiKey, model, err := a.config()
+	if err != nil {
+		return "", err
+	}
+
+	reqBody := anthropicRequest{
+		Model:     model,
+		System:    prompt.System,
+		Messages:  anthropicMessages(prompt),
+		MaxTokens: 1024,
+		Stream:    false,
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := a.newRequest(context.Background(), apiURL, apiKey, bodyBytes)
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request to Anthropic API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("anthropic API error: status code %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var anthropicResp anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&anthropicResp); err != nil {
+		return "", fmt.Errorf("failed to decode anthropic API response: %w", err)
+	}
+
+	metrics.ObserveLlamaRequestDuration(a.Name(), model, time.Since(start))
+	metrics.ObserveLlamaTokens(a.Name(), model, "prompt", anthropicResp.Usage.InputTokens)
+	metrics.ObserveLlamaTokens(a.Name(), model, "completion", anthropicResp.Usage.OutputTokens)
+
+	if len(anthropicResp.Content) == 0 {
+		return "", fmt.Errorf("no content blocks returned from Anthropic API")
+	}
+
+	return anthropicResp.Content[0].Text, nil
+}
+
+// AskStream sends a prompt to the Anthropic API with streaming enabled and
+// emits each text delta as it arrives. Anthropic's stream is SSE like
+// Groq's, but each event carries a "delta.text" field instead of
+// "delta.content", and the stream ends with a "message_stop" event rather
+// than a literal "[DONE]" marker.
+func (a AnthropicProvider) AskStream(ctx context.Context, prompt Prompt) (<-chan Token, <-chan error) {
+	tokens := make(chan Token)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(tokens)
+		defer close(errs)
+
+		apiURL, apiKey, model, err := a.config()
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		reqBody := anthropicRequest{
+			Model:     model,
+			System:    prompt.System,
+			Messages:  anthropicMessages(prompt),
+			MaxTokens: 1024,
+			Stream:    true,
+		}
+
+		bodyBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			errs <- fmt.Errorf("failed to marshal request body: %w", err)
+			return
+		}
+
+		req, err := a.newRequest(ctx, apiURL, apiKey, bodyBytes)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			errs <- fmt.Errorf("failed to send request to Anthropic API: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			errs <- fmt.Errorf("anthropic API error: status code %d, body: %s", resp.StatusCode, string(body))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		var event string
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				payload := strings.TrimPrefix(line, "data: ")
+
+				if event == "message_stop" {
+					select {
+					case tokens <- Token{Done: true}:
+					case <-ctx.Done():
+					}
+					return
+				}
+				if event != "content_block_delta" {
+					continue
+				}
+
+				var chunk anthropicStreamEvent
+				if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+					errs <- fmt.Errorf("failed to decode anthropic stream chunk: %w", err)
+					return
+				}
+				if chunk.Delta.Text == "" {
+					continue
+				}
+
+				select {
+				case tokens <- Token{Content: chunk.Delta.Text}:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errs <- fmt.Errorf("failed to read anthropic stream response: %w", err)
+		}
+	}()
+
+	return tokens, errs
+}
@@ -0,0 +1,74 @@
+// llama/anthropic_test.go
+package llama
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAnthropicAsk_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+
+		expectedKey := "FAKE_API_KEY"
+		if r.Header.Get("x-api-key") != expectedKey {
+			t.Errorf("Expected x-api-key header '%s', got '%s'", expectedKey, r.Header.Get("x-api-key"))
+		}
+		if r.Header.Get("anthropic-version") != anthropicAPIVersion {
+			t.Errorf("Expected anthropic-version header '%s', got '%s'", anthropicAPIVersion, r.Header.Get("anthropic-version"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		mockResponse := anthropicResponse{
+			Content:    []anthropicContentBlock{{Type: "text", Text: "Hello from the mock Anthropic server!"}},
+			StopReason: "end_turn",
+		}
+		json.NewEncoder(w).Encode(mockResponse)
+	}))
+	defer server.Close()
+
+	t.Setenv("ANTHROPIC_API_URL", server.URL)
+	t.Setenv("ANTHROPIC_API_KEY", "FAKE_API_KEY")
+	t.Setenv("ANTHROPIC_MODEL", "test-model")
+
+	prompt := Prompt{System: "test system", User: "test user"}
+	reply, err := AnthropicProvider{}.Ask(prompt)
+
+	if err != nil {
+		t.Fatalf("Ask() returned an unexpected error: %v", err)
+	}
+
+	expectedReply := "Hello from the mock Anthropic server!"
+	if reply != expectedReply {
+		t.Errorf("Expected reply '%s', got '%s'", expectedReply, reply)
+	}
+}
+
+func TestAnthropicAsk_ApiError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal Anthropic Error"))
+	}))
+	defer server.Close()
+
+	t.Setenv("ANTHROPIC_API_URL", server.URL)
+	t.Setenv("ANTHROPIC_API_KEY", "FAKE_API_KEY")
+	t.Setenv("ANTHROPIC_MODEL", "test-model")
+
+	prompt := Prompt{System: "test system", User: "test user"}
+	_, err := AnthropicProvider{}.Ask(prompt)
+
+	if err == nil {
+		t.Fatal("Ask() was expected to return an error, but it did not")
+	}
+
+	expectedErrorMsg := "anthropic API error: status code 500, body: Internal Anthropic Error"
+	if err.Error() != expectedErrorMsg {
+		t.Errorf("Expected error message '%s', got '%s'", expectedErrorMsg, err.Error())
+	}
+}
@@ -1,4 +1,4 @@
-// llama/llama_test.go
+// llama/groq_test.go
 package llama
 
 import (
@@ -46,7 +46,7 @@ func TestAsk_Success(t *testing.T) {
 
 	// 3. Call the function we are testing.
 	prompt := Prompt{System: "test system", User: "test user"}
-	reply, err := LlamaService.Ask(LlamaService{},prompt)
+	reply, err := GroqProvider.Ask(GroqProvider{},prompt)
 
 	// 4. Assert the results.
 	if err != nil {
@@ -75,7 +75,7 @@ func TestAsk_ApiError(t *testing.T) {
 
 	// 3. Call the function.
 	prompt := Prompt{System: "test system", User: "test user"}
-	_, err := LlamaService.Ask(LlamaService{}, prompt)
+	_, err := GroqProvider.Ask(GroqProvider{}, prompt)
 
 	// 4. Assert that we received an error.
 	if err == nil {
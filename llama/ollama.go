@@ -0,0 +1,212 @@
+package llama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/shayyz-code/raccoon-sku/backend/metrics"
+)
+
+// OllamaProvider talks to a local (or self-hosted) Ollama server's chat
+// API. Unlike the hosted providers it needs no API key, and its streaming
+// format is newline-delimited JSON objects rather than SSE "data: " frames.
+type OllamaProvider struct{}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+}
+
+// ollamaChatChunk is the shape of each line Ollama's /api/chat endpoint
+// writes, whether the response is a single line (Stream: false) or many
+// (Stream: true, one partial message per line, the last with Done: true).
+type ollamaChatChunk struct {
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+}
+
+// ollamaMessages converts a Prompt's turns (system, history, user) into the
+// ollamaMessage shape the API expects.
+func ollamaMessages(prompt Prompt) []ollamaMessage {
+	turns := prompt.Turns()
+	messages := make([]ollamaMessage, len(turns))
+	for i, t := range turns {
+		messages[i] = ollamaMessage{Role: t.Role, Content: t.Content}
+	}
+	return messages
+}
+
+// Name implements Provider.
+func (o OllamaProvider) Name() string { return "ollama" }
+
+// Models implements Provider.
+func (o OllamaProvider) Models() []string {
+	return []string{"llama3", "mistral"}
+}
+
+func (o OllamaProvider) config() (apiURL, model string, err error) {
+	apiURL = os.Getenv("OLLAMA_API_URL")
+	if apiURL == "" {
+		apiURL = "http://localhost:11434/api/chat"
+	}
+	model = os.Getenv("OLLAMA_MODEL")
+	if model == "" {
+		return "", "", fmt.Errorf("OLLAMA_MODEL environment variable is not set")
+	}
+	return apiURL, model, nil
+}
+
+// Ask sends a prompt to the Ollama API and returns the complete response.
+func (o OllamaProvider) Ask(prompt Prompt) (string, error) {
+	apiURL, model, err := o.config()
+	if err != nil {
+		return "", err
+	}
+
+	reqBody := ollamaRequest{
+		Model:    model,
+		Messages: ollamaMessages(prompt),
+		Stream:   false,
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to create new http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request to Ollama API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("ollama API error: status code %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var chunk ollamaChatChunk
+	if err := json.NewDecoder(resp.Body).Decode(&chunk); err != nil {
+		return "", fmt.Errorf("failed to decode ollama API response: %w", err)
+	}
+
+	metrics.ObserveLlamaRequestDuration(o.Name(), model, time.Since(start))
+	metrics.ObserveLlamaTokens(o.Name(), model, "prompt", chunk.PromptEvalCount)
+	metrics.ObserveLlamaTokens(o.Name(), model, "completion", chunk.EvalCount)
+
+	return chunk.Message.Content, nil
+}
+
+// AskStream sends a prompt to the Ollama API with streaming enabled and
+// emits each token as it arrives. Ollama writes one JSON object per line
+// rather than SSE "data: " frames, so this scans lines and decodes each
+// directly instead of stripping a prefix.
+func (o OllamaProvider) AskStream(ctx context.Context, prompt Prompt) (<-chan Token, <-chan error) {
+	tokens := make(chan Token)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(tokens)
+		defer close(errs)
+
+		apiURL, model, err := o.config()
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		reqBody := ollamaRequest{
+			Model:    model,
+			Messages: ollamaMessages(prompt),
+			Stream:   true,
+		}
+
+		bodyBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			errs <- fmt.Errorf("failed to marshal request body: %w", err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(bodyBytes))
+		if err != nil {
+			errs <- fmt.Errorf("failed to create new http request: %w", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			errs <- fmt.Errorf("failed to send request to Ollama API: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			errs <- fmt.Errorf("ollama API error: status code %d, body: %s", resp.StatusCode, string(body))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+
+			var chunk ollamaChatChunk
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				errs <- fmt.Errorf("failed to decode ollama stream chunk: %w", err)
+				return
+			}
+
+			if chunk.Done {
+				select {
+				case tokens <- Token{Done: true}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if chunk.Message.Content == "" {
+				continue
+			}
+
+			select {
+			case tokens <- Token{Content: chunk.Message.Content}:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errs <- fmt.Errorf("failed to read ollama stream response: %w", err)
+		}
+	}()
+
+	return tokens, errs
+}
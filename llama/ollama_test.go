@@ -0,0 +1,64 @@
+// llama/ollama_test.go
+package llama
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOllamaAsk_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		mockResponse := ollamaChatChunk{
+			Message: ollamaMessage{Role: "assistant", Content: "Hello from the mock Ollama server!"},
+			Done:    true,
+		}
+		json.NewEncoder(w).Encode(mockResponse)
+	}))
+	defer server.Close()
+
+	t.Setenv("OLLAMA_API_URL", server.URL)
+	t.Setenv("OLLAMA_MODEL", "test-model")
+
+	prompt := Prompt{System: "test system", User: "test user"}
+	reply, err := OllamaProvider{}.Ask(prompt)
+
+	if err != nil {
+		t.Fatalf("Ask() returned an unexpected error: %v", err)
+	}
+
+	expectedReply := "Hello from the mock Ollama server!"
+	if reply != expectedReply {
+		t.Errorf("Expected reply '%s', got '%s'", expectedReply, reply)
+	}
+}
+
+func TestOllamaAsk_ApiError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal Ollama Error"))
+	}))
+	defer server.Close()
+
+	t.Setenv("OLLAMA_API_URL", server.URL)
+	t.Setenv("OLLAMA_MODEL", "test-model")
+
+	prompt := Prompt{System: "test system", User: "test user"}
+	_, err := OllamaProvider{}.Ask(prompt)
+
+	if err == nil {
+		t.Fatal("Ask() was expected to return an error, but it did not")
+	}
+
+	expectedErrorMsg := "ollama API error: status code 500, body: Internal Ollama Error"
+	if err.Error() != expectedErrorMsg {
+		t.Errorf("Expected error message '%s', got '%s'", expectedErrorMsg, err.Error())
+	}
+}
@@ -0,0 +1,245 @@
+package llama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shayyz-code/raccoon-sku/backend/metrics"
+)
+
+// OpenAIProvider talks to the OpenAI chat completions API. Like
+// GroqProvider it's stateless and re-reads its OPENAI_* environment
+// variables on every call.
+type OpenAIProvider struct{}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	Temperature float32         `json:"temperature,omitempty"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Stream      bool            `json:"stream"`
+}
+
+type openAIResponse struct {
+	Choices []openAIChoice `json:"choices"`
+	Usage   openAIUsage    `json:"usage"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type openAIChoice struct {
+	Message      *openAIMessage `json:"message"`
+	FinishReason string         `json:"finish_reason"`
+}
+
+type openAIStreamChunk struct {
+	Choices []openAIStreamChoice `json:"choices"`
+}
+
+type openAIStreamChoice struct {
+	Delta openAIStreamDelta `json:"delta"`
+}
+
+type openAIStreamDelta struct {
+	Content string `json:"content"`
+}
+
+// openAIMessages converts a Prompt's turns (system, history, user) into the
+// openAIMessage shape the API expects.
+func openAIMessages(prompt Prompt) []openAIMessage {
+	turns := prompt.Turns()
+	messages := make([]openAIMessage, len(turns))
+	for i, t := range turns {
+		messages[i] = openAIMessage{Role: t.Role, Content: t.Content}
+	}
+	return messages
+}
+
+// Name implements Provider.
+func (o OpenAIProvider) Name() string { return "openai" }
+
+// Models implements Provider.
+func (o OpenAIProvider) Models() []string {
+	return []string{"gpt-4o", "gpt-4o-mini"}
+}
+
+func (o OpenAIProvider) config() (apiURL, apiKey, model string, err error) {
+	apiURL = os.Getenv("OPENAI_API_URL")
+	if apiURL == "" {
+		apiURL = "https://api.openai.com/v1/chat/completions"
+	}
+	apiKey = os.Getenv("OPENAI_API_KEY")
+	model = os.Getenv("OPENAI_MODEL")
+
+	if apiKey == "" || model == "" {
+		return "", "", "", fmt.Errorf("one or more OpenAI environment variables are not set: OPENAI_API_KEY, OPENAI_MODEL")
+	}
+	return apiURL, apiKey, model, nil
+}
+
+// Ask sends a prompt to the OpenAI API and returns the complete response.
+func (o OpenAIProvider) Ask(prompt Prompt) (string, error) {
+	apiURL, apiKey, model, err := o.config()
+	if err != nil {
+		return "", err
+	}
+
+	reqBody := openAIRequest{
+		Model:       model,
+		Messages:    openAIMessages(prompt),
+		Temperature: 0.7,
+		MaxTokens:   1024,
+		Stream:      false,
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to create new http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	client := &http.Client{}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request to OpenAI API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("openai API error: status code %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var openAIResp openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&openAIResp); err != nil {
+		return "", fmt.Errorf("failed to decode openai API response: %w", err)
+	}
+
+	metrics.ObserveLlamaRequestDuration(o.Name(), model, time.Since(start))
+	metrics.ObserveLlamaTokens(o.Name(), model, "prompt", openAIResp.Usage.PromptTokens)
+	metrics.ObserveLlamaTokens(o.Name(), model, "completion", openAIResp.Usage.CompletionTokens)
+
+	if len(openAIResp.Choices) == 0 || openAIResp.Choices[0].Message == nil {
+		return "", fmt.Errorf("no choices returned from OpenAI API")
+	}
+
+	return openAIResp.Choices[0].Message.Content, nil
+}
+
+// AskStream sends a prompt to the OpenAI API with streaming enabled and
+// emits each token as it arrives, mirroring GroqProvider.AskStream since
+// OpenAI's SSE chunk format is the same "data: {...}" / "data: [DONE]"
+// shape Groq's API is modeled on.
+func (o OpenAIProvider) AskStream(ctx context.Context, prompt Prompt) (<-chan Token, <-chan error) {
+	tokens := make(chan Token)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(tokens)
+		defer close(errs)
+
+		apiURL, apiKey, model, err := o.config()
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		reqBody := openAIRequest{
+			Model:       model,
+			Messages:    openAIMessages(prompt),
+			Temperature: 0.7,
+			MaxTokens:   1024,
+			Stream:      true,
+		}
+
+		bodyBytes, err := json.Marshal(reqBody)
+		if err != nil {
+			errs <- fmt.Errorf("failed to marshal request body: %w", err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(bodyBytes))
+		if err != nil {
+			errs <- fmt.Errorf("failed to create new http request: %w", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			errs <- fmt.Errorf("failed to send request to OpenAI API: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			errs <- fmt.Errorf("openai API error: status code %d, body: %s", resp.StatusCode, string(body))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+
+			payload := strings.TrimPrefix(line, "data: ")
+			if payload == "[DONE]" {
+				select {
+				case tokens <- Token{Done: true}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			var chunk openAIStreamChunk
+			if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+				errs <- fmt.Errorf("failed to decode openai stream chunk: %w", err)
+				return
+			}
+			if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+				continue
+			}
+
+			select {
+			case tokens <- Token{Content: chunk.Choices[0].Delta.Content}:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errs <- fmt.Errorf("failed to read openai stream response: %w", err)
+		}
+	}()
+
+	return tokens, errs
+}
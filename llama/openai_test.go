@@ -0,0 +1,75 @@
+// llama/openai_test.go
+package llama
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenAIAsk_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+
+		expectedAuth := "Bearer FAKE_API_KEY"
+		if r.Header.Get("Authorization") != expectedAuth {
+			t.Errorf("Expected Authorization header '%s', got '%s'", expectedAuth, r.Header.Get("Authorization"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		mockResponse := openAIResponse{
+			Choices: []openAIChoice{
+				{
+					Message:      &openAIMessage{Role: "assistant", Content: "Hello from the mock OpenAI server!"},
+					FinishReason: "stop",
+				},
+			},
+		}
+		json.NewEncoder(w).Encode(mockResponse)
+	}))
+	defer server.Close()
+
+	t.Setenv("OPENAI_API_URL", server.URL)
+	t.Setenv("OPENAI_API_KEY", "FAKE_API_KEY")
+	t.Setenv("OPENAI_MODEL", "test-model")
+
+	prompt := Prompt{System: "test system", User: "test user"}
+	reply, err := OpenAIProvider{}.Ask(prompt)
+
+	if err != nil {
+		t.Fatalf("Ask() returned an unexpected error: %v", err)
+	}
+
+	expectedReply := "Hello from the mock OpenAI server!"
+	if reply != expectedReply {
+		t.Errorf("Expected reply '%s', got '%s'", expectedReply, reply)
+	}
+}
+
+func TestOpenAIAsk_ApiError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal OpenAI Error"))
+	}))
+	defer server.Close()
+
+	t.Setenv("OPENAI_API_URL", server.URL)
+	t.Setenv("OPENAI_API_KEY", "FAKE_API_KEY")
+	t.Setenv("OPENAI_MODEL", "test-model")
+
+	prompt := Prompt{System: "test system", User: "test user"}
+	_, err := OpenAIProvider{}.Ask(prompt)
+
+	if err == nil {
+		t.Fatal("Ask() was expected to return an error, but it did not")
+	}
+
+	expectedErrorMsg := "openai API error: status code 500, body: Internal OpenAI Error"
+	if err.Error() != expectedErrorMsg {
+		t.Errorf("Expected error message '%s', got '%s'", expectedErrorMsg, err.Error())
+	}
+}
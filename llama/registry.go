@@ -0,0 +1,38 @@
+package llama
+
+import (
+	"fmt"
+	"os"
+)
+
+// Registry holds every Provider this build knows how to talk to, keyed by
+// the same lowercase name each returns from Name(). main wires up the
+// active one from LLM_PROVIDER at startup; handlers can also look one up
+// per-request via the optional "provider" field on the request body.
+var Registry = map[string]Provider{
+	"groq":      GroqProvider{},
+	"openai":    OpenAIProvider{},
+	"ollama":    OllamaProvider{},
+	"anthropic": AnthropicProvider{},
+}
+
+// Default returns the Provider selected by LLM_PROVIDER, falling back to
+// "groq" so existing deployments that don't set it keep working unchanged.
+func Default() (Provider, error) {
+	name := os.Getenv("LLM_PROVIDER")
+	if name == "" {
+		name = "groq"
+	}
+	return Lookup(name)
+}
+
+// Lookup returns the registered Provider for name, or an error if the build
+// doesn't know about it (e.g. a typo in LLM_PROVIDER or an unrecognized
+// "provider" field in a request body).
+func Lookup(name string) (Provider, error) {
+	p, ok := Registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown LLM provider %q", name)
+	}
+	return p, nil
+}
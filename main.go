@@ -8,40 +8,158 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"github.com/shayyz-code/raccoon-sku/backend/contextKeys"
+	"github.com/shayyz-code/raccoon-sku/backend/conversation"
 	"github.com/shayyz-code/raccoon-sku/backend/jwt"
 	"github.com/shayyz-code/raccoon-sku/backend/limiter"
 	"github.com/shayyz-code/raccoon-sku/backend/llama"
+	"github.com/shayyz-code/raccoon-sku/backend/metrics"
 )
 
-// 1. Define an interface for our business logic.
-// This allows us to use the real `llama` package in production
-// and a fake "mock" version in our tests.
-type asker interface {
-	Ask(prompt llama.Prompt) (string, error)
+// streamWriteTimeout bounds how long the /api/ask-llama/stream handler will
+// keep a connection open waiting on the upstream provider. It's kept well
+// below typical load-balancer idle timeouts so we always get a chance to
+// write a final SSE error frame instead of the client just hanging.
+var streamWriteTimeout = 2 * time.Minute
+
+func init() {
+	if v := os.Getenv("STREAM_WRITE_TIMEOUT_SECONDS"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+			streamWriteTimeout = time.Duration(secs) * time.Second
+		}
+	}
 }
 
 // 2. Create an application struct to hold dependencies.
+// ai is the default llama.Provider for this deployment (selected by
+// LLM_PROVIDER); handlers can still fan out to a different registered
+// provider per-request via the request body's optional "provider" field.
+// Using the llama.Provider interface here, rather than a concrete struct,
+// is what lets tests swap in a fake provider without hitting a real API.
 type application struct {
-	ai asker
+	ai        llama.Provider
+	metrics   metrics.Metrics
+	convStore *conversation.Store
 }
 
-var rl *limiter.RateLimiter
+var maxInFlight func(http.Handler) http.Handler
+var rateLimitHeaders func(http.Handler) http.Handler
+var authVerifier jwt.Verifier
+
+// defaultLongRunningRequestRE matches endpoints expected to hold an
+// in-flight slot for minutes rather than seconds, e.g. the SSE streaming
+// endpoint, so they're exempted from the MaxInFlight semaphore.
+const defaultLongRunningRequestRE = `^/api/ask-llama/stream$`
+
+// conversationSummarizeTokenBudget/conversationSummarizeKeepRecent control
+// when handleAskLlama condenses older turns: once a conversation's
+// estimated token count exceeds the budget, everything but the most recent
+// keepRecent messages is replaced with a single summary message.
+const (
+	conversationSummarizeTokenBudget = 3000
+	conversationSummarizeKeepRecent  = 6
+)
+
+// initRateLimitHeaders sets up the multi-rule sliding-window limiter
+// (per-minute burst + per-day quota, both operator-configurable) that is
+// now the sole rate limit enforced on /api routes: it both rejects requests
+// that exceed either rule and reports X-RateLimit-* headers on the rest.
+func initRateLimitHeaders(addr *string) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr: *addr,
+	})
+
+	perMinute := 20
+	if v := os.Getenv("RATE_LIMIT_PER_MINUTE"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			perMinute = parsed
+		}
+	}
+
+	perDay := 200
+	if v := os.Getenv("RATE_LIMIT_PER_DAY"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			perDay = parsed
+		}
+	}
+
+	rules := []limiter.Rule{
+		{Name: "per-minute", Limit: perMinute, Window: time.Minute},
+		{Name: "per-day", Limit: perDay, Window: 24 * time.Hour},
+	}
+
+	rateLimitHeaders = limiter.Middleware(limiter.NewMultiRateLimiter(rdb, rules), func(r *http.Request) string {
+		userID, _ := r.Context().Value(contextKeys.UserIDKey).(string)
+		return userID
+	})
+}
 
-func initRateLimiter(addr *string) {
+func initConversationStore(addr *string) *conversation.Store {
 	rdb := redis.NewClient(&redis.Options{
 		Addr: *addr,
 	})
-	rl = limiter.NewRateLimiter(rdb, 20, 24 * time.Hour) // 20 requests per day
+
+	ttl := 24 * time.Hour
+	if v := os.Getenv("CONVERSATION_TTL_SECONDS"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+			ttl = time.Duration(secs) * time.Second
+		}
+	}
+
+	maxLen := 50
+	if v := os.Getenv("CONVERSATION_MAX_LEN"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			maxLen = parsed
+		}
+	}
+
+	return conversation.NewStore(rdb, ttl, maxLen)
+}
+
+// initAuthVerifier selects the jwt.Verifier used by apiMiddleware, per
+// AUTH_MODE; it's fatal at startup rather than per-request since a
+// misconfigured AUTH_MODE/OIDC_ISSUER means the deployment can never
+// authenticate anyone.
+func initAuthVerifier() {
+	v, err := jwt.Default()
+	if err != nil {
+		log.Fatalf("Failed to configure auth verifier: %v", err)
+	}
+	authVerifier = v
+}
+
+func initMaxInFlight() {
+	n := 64
+	if v := os.Getenv("MAX_REQUESTS_IN_FLIGHT"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			n = parsed
+		}
+	}
+
+	reStr := os.Getenv("LONG_RUNNING_REQUEST_RE")
+	if reStr == "" {
+		reStr = defaultLongRunningRequestRE
+	}
+
+	longRunningRE, err := regexp.Compile(reStr)
+	if err != nil {
+		log.Fatalf("invalid LONG_RUNNING_REQUEST_RE %q: %v", reStr, err)
+	}
+
+	maxInFlight = limiter.MaxInFlight(n, longRunningRE)
 }
 
 func main() {
-	
+
 	if err := godotenv.Load(); err != nil {
 		log.Println("Warning: Could not load .env file.")
 	}
@@ -51,44 +169,168 @@ func main() {
 		port = "8080"
 	}
 
-	// 3. In main, we create the app with the REAL implementation.
-	app := &application{
-		ai: llama.LlamaService{}, // Use a struct that implements the interface
-	}
-
-	r := mux.NewRouter()
-
-
 	redisAddr := os.Getenv("REDIS_ADDR")
 
 	if redisAddr == "" {
 		redisAddr = "localhost:6379"
 	}
 
-	initRateLimiter(&redisAddr)
+	// 3. In main, we create the app with the REAL implementation, selected
+	// from the registry by LLM_PROVIDER.
+	provider, err := llama.Default()
+	if err != nil {
+		log.Fatalf("Failed to select LLM provider: %v", err)
+	}
+	app := &application{
+		ai:        provider,
+		metrics:   metrics.NewPrometheus(),
+		convStore: initConversationStore(&redisAddr),
+	}
+
+	r := mux.NewRouter()
+	r.Use(app.metricsMiddleware)
+
+	initAuthVerifier()
+	initRateLimitHeaders(&redisAddr)
+	initMaxInFlight()
 
 	r.HandleFunc("/create-api-key", app.handleCreateAPIKey).Methods("POST")
+	// Guarded by its own METRICS_TOKEN rather than user JWT, since scrapers
+	// aren't users and shouldn't need an api-key.
+	r.HandleFunc("/metrics", handleMetrics).Methods("GET")
 	// The handler is now a method on our app struct
-	// Protect all /api routes
-	r.PathPrefix("/api/").Handler(apiMiddleware(http.HandlerFunc(app.apiRouter)))
+	// Protect all /api routes. maxInFlight wraps apiMiddleware so a request
+	// that would be rejected for lack of a concurrency slot never bothers
+	// doing a JWT parse first. rateLimitHeaders sits inside apiMiddleware so
+	// it can read the user ID apiMiddleware puts in context, and is now the
+	// sole rate-limit enforcement point for /api routes (see
+	// observeRateLimitRejections).
+	r.PathPrefix("/api/").Handler(maxInFlight(apiMiddleware(app.observeRateLimitRejections(rateLimitHeaders(http.HandlerFunc(app.apiRouter))))))
 
 	log.Println("Server listening on port", port)
 
-	if err := http.ListenAndServe(":"+port, r); err != nil {
+	// WriteTimeout is disabled at the server level because the streaming
+	// endpoint can legitimately hold a connection open for minutes; instead
+	// handleAskLlamaStream enforces its own deadline via streamWriteTimeout
+	// and writes an explicit SSE error frame when it's hit.
+	server := &http.Server{
+		Addr:         ":" + port,
+		Handler:      r,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 0,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	if err := server.ListenAndServe(); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
 
 func (app *application) apiRouter(w http.ResponseWriter, r *http.Request) {
 	log.Println(r.URL.Path)
-	switch r.URL.Path {
-	case "/api/ask-llama":
+	switch {
+	case r.URL.Path == "/api/ask-llama":
 		app.handleAskLlama(w, r)
+	case r.URL.Path == "/api/ask-llama/stream":
+		app.handleAskLlamaStream(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/conversations/"):
+		app.handleConversation(w, r)
 	default:
 		http.NotFound(w, r)
 	}
 }
 
+// statusRecorder wraps a ResponseWriter to capture the status code written,
+// for metricsMiddleware's http_requests_total label. It forwards Flush so
+// the streaming handler still sees an http.Flusher through the wrapper.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// observeRateLimitRejections records rate_limit_rejections_total for
+// requests rejected by rateLimitHeaders, which is the sole rate-limit
+// enforcement point on /api routes now that handleAskLlama/
+// handleAskLlamaStream no longer duplicate the check with their own
+// (conflicting) daily quota. It must sit outside rateLimitHeaders in the
+// chain so it can observe the 429 rateLimitHeaders writes.
+func (app *application) observeRateLimitRejections(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		if rec.status == http.StatusTooManyRequests {
+			userID, _ := r.Context().Value(contextKeys.UserIDKey).(string)
+			app.metrics.ObserveRateLimitRejection(userID)
+		}
+	})
+}
+
+// metricsMiddleware records http_requests_total and
+// http_request_duration_seconds for every request, labeled by route.
+func (app *application) metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		app.metrics.ObserveHTTPRequest(metricsRouteLabel(r), r.Method, strconv.Itoa(rec.status), time.Since(start))
+	})
+}
+
+// metricsRouteLabel returns the route label to use for http_requests_total
+// and http_request_duration_seconds. All of /api/* is served by a single
+// mux.Router PathPrefix("/api/") route, so matched.GetPathTemplate() would
+// collapse ask-llama, the stream endpoint, and every conversation request
+// down to the same "/api/" template. Classify those paths the same way
+// apiRouter itself dispatches them instead, so the label actually
+// distinguishes the real API routes; anything outside /api/ still uses the
+// matched mux template (falling back to the raw path if none matched) so
+// path parameters don't blow up cardinality.
+func metricsRouteLabel(r *http.Request) string {
+	switch {
+	case r.URL.Path == "/api/ask-llama":
+		return "/api/ask-llama"
+	case r.URL.Path == "/api/ask-llama/stream":
+		return "/api/ask-llama/stream"
+	case strings.HasPrefix(r.URL.Path, "/api/conversations/"):
+		return "/api/conversations/{id}"
+	}
+
+	route := r.URL.Path
+	if matched := mux.CurrentRoute(r); matched != nil {
+		if tpl, err := matched.GetPathTemplate(); err == nil {
+			route = tpl
+		}
+	}
+	return route
+}
+
+// handleMetrics serves Prometheus text exposition, gated by METRICS_TOKEN
+// rather than the user JWT scheme the rest of /api uses, since scrapers
+// aren't a "user" in that sense.
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	token := os.Getenv("METRICS_TOKEN")
+	if token == "" || r.Header.Get("X-Metrics-Token") != token {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	promhttp.Handler().ServeHTTP(w, r)
+}
+
 func apiMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// 1. Check JWT from Authorization header
@@ -99,8 +341,9 @@ func apiMiddleware(next http.Handler) http.Handler {
 		}
 		token := auth[7:]
 
-		// 2. Parse and verify JWT
-		userID, err := jwt.ParseJWT(token)
+		// 2. Verify the token via the configured Verifier (HS256 shared
+		// secret or OIDC, per AUTH_MODE).
+		userID, err := authVerifier.Verify(token)
 		if err != nil {
 			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
 			return
@@ -171,34 +414,63 @@ func (app *application) handleAskLlama(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// limiter
+	// Rate limiting is enforced upstream by rateLimitHeaders; it and
+	// observeRateLimitRejections already reject with 429 before this handler
+	// ever runs, so there's nothing left to check here.
 
-	allowed, err := rl.Allow(userID)
-	if err != nil {
-		http.Error(w, "Rate limit check failed", http.StatusInternalServerError)
-		return
+	var req struct {
+		SystemPrompt   string `json:"systemPrompt"`
+		UserPrompt     string `json:"userPrompt"`
+		Provider       string `json:"provider"`
+		ConversationID string `json:"conversationId"`
 	}
 
-	if !allowed {
-		http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	var req struct {
-		SystemPrompt string `json:"systemPrompt"`
-		UserPrompt   string `json:"userPrompt"`
+	// It calls the dependency via the interface, unless the caller asked
+	// for a specific provider.
+	service := app.ai
+	if req.Provider != "" {
+		p, err := llama.Lookup(req.Provider)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		service = p
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	conversationID := req.ConversationID
+	if conversationID == "" {
+		conversationID = conversation.NewID()
+	}
+
+	history, err := app.convStore.Messages(r.Context(), userID, conversationID)
+	if err != nil {
+		log.Printf("Error loading conversation %s: %v", conversationID, err)
+		http.Error(w, "Failed to load conversation history", http.StatusInternalServerError)
 		return
 	}
 
-	// It calls the dependency via the interface.
-	service := llama.LlamaService{}
+	originalLen := len(history)
+	history, err = conversation.Summarize(service, history, conversationSummarizeTokenBudget, conversationSummarizeKeepRecent)
+	if err != nil {
+		// Summarization failing isn't fatal to the turn; fall back to the
+		// un-summarized history and let Ask's own context-length handling
+		// (or the upstream's) deal with it.
+		log.Printf("Error summarizing conversation %s: %v", conversationID, err)
+	} else if len(history) != originalLen {
+		if err := app.convStore.Replace(r.Context(), userID, conversationID, history); err != nil {
+			log.Printf("Error persisting summarized conversation %s: %v", conversationID, err)
+		}
+	}
+
 	prompt := llama.Prompt{
-		System: req.SystemPrompt,
-		User:   req.UserPrompt,
+		System:  req.SystemPrompt,
+		User:    req.UserPrompt,
+		History: history,
 	}
 
 	response, err := service.Ask(prompt) // IMPORTANT: Check the error here!
@@ -210,9 +482,184 @@ func (app *application) handleAskLlama(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := app.convStore.Append(r.Context(), userID, conversationID, llama.Message{Role: "user", Content: req.UserPrompt}); err != nil {
+		log.Printf("Error appending user turn to conversation %s: %v", conversationID, err)
+	}
+	if err := app.convStore.Append(r.Context(), userID, conversationID, llama.Message{Role: "assistant", Content: response}); err != nil {
+		log.Printf("Error appending assistant turn to conversation %s: %v", conversationID, err)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{
-		"reply": response,
+		"reply":          response,
+		"conversationId": conversationID,
 	})
-}
\ No newline at end of file
+}
+
+// handleConversation serves GET (retrieve) and DELETE (clear) requests for
+// a single conversation's stored history, at /api/conversations/{id}.
+func (app *application) handleConversation(w http.ResponseWriter, r *http.Request) {
+	userVal := r.Context().Value(contextKeys.UserIDKey)
+	userID, ok := userVal.(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: no user ID in context", http.StatusUnauthorized)
+		return
+	}
+
+	conversationID := strings.TrimPrefix(r.URL.Path, "/api/conversations/")
+	if conversationID == "" {
+		http.Error(w, "Missing conversation id", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		messages, err := app.convStore.Messages(r.Context(), userID, conversationID)
+		if err != nil {
+			log.Printf("Error loading conversation %s: %v", conversationID, err)
+			http.Error(w, "Failed to load conversation", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"conversationId": conversationID,
+			"messages":       messages,
+		})
+
+	case http.MethodDelete:
+		if err := app.convStore.Delete(r.Context(), userID, conversationID); err != nil {
+			log.Printf("Error deleting conversation %s: %v", conversationID, err)
+			http.Error(w, "Failed to delete conversation", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAskLlamaStream is the SSE counterpart to handleAskLlama: instead of
+// waiting for the complete reply it flushes each token to the client as the
+// provider produces it. It goes through the same auth and rate-limit checks
+// as handleAskLlama since both are reached via the shared apiMiddleware/
+// apiRouter chain.
+func (app *application) handleAskLlamaStream(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userVal := r.Context().Value(contextKeys.UserIDKey)
+	userID, ok := userVal.(string)
+	if !ok || userID == "" {
+		http.Error(w, "Unauthorized: no user ID in context", http.StatusUnauthorized)
+		return
+	}
+
+	// Rate limiting is enforced upstream by rateLimitHeaders; see
+	// handleAskLlama.
+
+	var req struct {
+		SystemPrompt string `json:"systemPrompt"`
+		UserPrompt   string `json:"userPrompt"`
+		Provider     string `json:"provider"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	service := app.ai
+	if req.Provider != "" {
+		p, err := llama.Lookup(req.Provider)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		service = p
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// Bound how long we'll wait on the upstream so a slow/stalled provider
+	// can't hold this connection open forever; ctx.Done() also fires if the
+	// client disconnects, via r.Context().
+	ctx, cancel := context.WithTimeout(r.Context(), streamWriteTimeout)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Chunked transfer is what lets us flush tokens incrementally; disable
+	// any intermediary buffering (e.g. nginx) that would defeat that.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	prompt := llama.Prompt{
+		System: req.SystemPrompt,
+		User:   req.UserPrompt,
+	}
+
+	tokens, errs := service.AskStream(ctx, prompt)
+
+	for {
+		select {
+		case tok, open := <-tokens:
+			if !open {
+				return
+			}
+			if tok.Done {
+				fmt.Fprint(w, "data: [DONE]\n\n")
+				flusher.Flush()
+				return
+			}
+			payload, _ := json.Marshal(map[string]string{"content": tok.Content})
+			fmt.Fprintf(w, "data: %s\n\n", payload)
+			flusher.Flush()
+
+		case err, open := <-errs:
+			if !open {
+				return
+			}
+			if err != nil {
+				writeSSEError(w, err)
+				flusher.Flush()
+			}
+			return
+
+		case <-ctx.Done():
+			writeSSEError(w, ctx.Err())
+			flusher.Flush()
+			return
+		}
+	}
+}
+
+// writeSSEError writes a terminal "event: error" SSE frame. By the time this
+// runs the 200 response has already been written and flushed in chunked
+// mode, so there's no way to attach a genuine Content-Length to this frame;
+// callers must flush after calling this and clients must rely on the
+// trailing blank line (and the connection closing) to know the frame is
+// complete.
+func writeSSEError(w http.ResponseWriter, err error) {
+	body, _ := json.Marshal(map[string]string{"error": err.Error()})
+	fmt.Fprintf(w, "event: error\ndata: %s\n\n", body)
+}
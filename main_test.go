@@ -3,16 +3,20 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
+	"github.com/shayyz-code/raccoon-sku/backend/contextKeys"
 	"github.com/shayyz-code/raccoon-sku/backend/llama"
+	"github.com/shayyz-code/raccoon-sku/backend/metrics"
 )
 
-// 1. Create a mock struct that satisfies the `asker` interface.
+// 1. Create a mock struct that satisfies the `llama.Provider` interface.
 type mockAsker struct{}
 
 // This is our mock Ask method. It doesn't make any network calls.
@@ -24,20 +28,51 @@ func (m *mockAsker) Ask(prompt llama.Prompt) (string, error) {
 	return "mocked AI reply", nil
 }
 
+func (m *mockAsker) Name() string { return "mock" }
+
+func (m *mockAsker) Models() []string { return []string{"mock-model"} }
+
+func (m *mockAsker) AskStream(ctx context.Context, prompt llama.Prompt) (<-chan llama.Token, <-chan error) {
+	tokens := make(chan llama.Token, 1)
+	errs := make(chan error, 1)
+	tokens <- llama.Token{Done: true}
+	close(tokens)
+	close(errs)
+	return tokens, errs
+}
+
 func TestHandleAskLlama_Success(t *testing.T) {
+	// convStore isn't injected via a fake the way ai/metrics are, since
+	// conversation.Store wraps a real *redis.Client with no interface seam;
+	// bootstrap it the same way main() does, against a real REDIS_ADDR (or
+	// the same localhost:6379 default main() falls back to).
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+
 	// Create an application instance using our MOCK asker.
 	app := &application{
-		ai: &mockAsker{},
+		ai:        &mockAsker{},
+		metrics:   metrics.NoOp{},
+		convStore: initConversationStore(&addr),
 	}
 
-	// Create a sample JSON request body.
+	// Create a sample JSON request body. Pinning conversationId keeps the
+	// expected response body below deterministic instead of depending on a
+	// freshly generated conversation.NewID().
 	requestBody, _ := json.Marshal(map[string]string{
-		"systemPrompt": "test system",
-		"userPrompt":   "test user",
+		"systemPrompt":   "test system",
+		"userPrompt":     "test user",
+		"conversationId": "test-convo",
 	})
 
 	// Create a new HTTP request and a recorder to capture the response.
+	// apiMiddleware is what normally puts the user ID in context after
+	// verifying the JWT; set it directly since we're calling the handler
+	// below it.
 	req := httptest.NewRequest("POST", "/api/ask-llama", bytes.NewBuffer(requestBody))
+	req = req.WithContext(context.WithValue(req.Context(), contextKeys.UserIDKey, "test-user"))
 	rr := httptest.NewRecorder()
 
 	// Call the handler directly.
@@ -49,7 +84,7 @@ func TestHandleAskLlama_Success(t *testing.T) {
 	}
 
 	// Assert the response body.
-	expected := `{"reply":"mocked AI reply"}` + "\n"
+	expected := `{"conversationId":"test-convo","reply":"mocked AI reply"}` + "\n"
 	if rr.Body.String() != expected {
 		t.Errorf("handler returned unexpected body: got %v want %v", rr.Body.String(), expected)
 	}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// llama_request_duration_seconds and llama_tokens_total are recorded
+// directly from inside the providers that have the numbers to hand (e.g.
+// GroqProvider.Ask, which sees GroqResponse.Usage), not through the
+// Metrics interface: unlike the HTTP-level metrics, they don't need to be
+// swapped out in handler tests, so package-level collectors are simpler,
+// the same way limiter's in-flight counters are plain expvar vars.
+var (
+	llamaRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "llama_request_duration_seconds",
+		Help:    "Upstream LLM provider call duration in seconds, by provider and model.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider", "model"})
+
+	llamaTokensTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "llama_tokens_total",
+		Help: `Total tokens used in provider calls, by provider, model and kind ("prompt" or "completion").`,
+	}, []string{"provider", "model", "kind"})
+)
+
+// ObserveLlamaRequestDuration records how long a single upstream Ask call
+// took.
+func ObserveLlamaRequestDuration(provider, model string, duration time.Duration) {
+	llamaRequestDuration.WithLabelValues(provider, model).Observe(duration.Seconds())
+}
+
+// ObserveLlamaTokens adds count tokens of the given kind ("prompt" or
+// "completion") to the running total for provider/model.
+func ObserveLlamaTokens(provider, model, kind string, count int) {
+	if count <= 0 {
+		return
+	}
+	llamaTokensTotal.WithLabelValues(provider, model, kind).Add(float64(count))
+}
@@ -0,0 +1,20 @@
+// Package metrics is the seam between the HTTP layer and whatever collects
+// request/rate-limit numbers. Handlers and middleware talk to the Metrics
+// interface rather than a concrete collector, the same way they talk to
+// llama.Provider instead of a concrete provider, so tests can swap in NoOp
+// instead of needing a live Prometheus registry.
+package metrics
+
+import "time"
+
+// Metrics records the counters and histograms the HTTP layer cares about.
+type Metrics interface {
+	// ObserveHTTPRequest records one completed request: http_requests_total
+	// incremented for {route,method,status}, and duration recorded against
+	// http_request_duration_seconds for {route,method}.
+	ObserveHTTPRequest(route, method, status string, duration time.Duration)
+	// ObserveRateLimitRejection increments rate_limit_rejections_total for
+	// user. Callers are expected to bucket/hash user themselves before
+	// calling this, since raw user IDs would blow up label cardinality.
+	ObserveRateLimitRejection(user string)
+}
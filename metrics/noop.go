@@ -0,0 +1,10 @@
+package metrics
+
+import "time"
+
+// NoOp is a Metrics implementation that discards everything, so handler
+// tests don't need a live Prometheus registry to construct an application.
+type NoOp struct{}
+
+func (NoOp) ObserveHTTPRequest(route, method, status string, duration time.Duration) {}
+func (NoOp) ObserveRateLimitRejection(user string)                                   {}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// rateLimitUserBuckets bounds the cardinality of the user label on
+// rate_limit_rejections_total: raw user IDs are hashed and folded into a
+// fixed number of buckets instead of used directly.
+const rateLimitUserBuckets = 64
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests handled, by route, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request handling duration in seconds, by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	rateLimitRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limit_rejections_total",
+		Help: "Total number of requests rejected by the rate limiter, by (bucketed) user.",
+	}, []string{"user"})
+)
+
+// Prometheus is the production Metrics implementation, backed by the
+// default Prometheus registry; /metrics serves it via promhttp.Handler.
+type Prometheus struct{}
+
+// NewPrometheus returns a Prometheus Metrics implementation. Its collectors
+// are package-level and registered once via promauto, so constructing more
+// than one Prometheus is safe but pointless.
+func NewPrometheus() Prometheus {
+	return Prometheus{}
+}
+
+func (Prometheus) ObserveHTTPRequest(route, method, status string, duration time.Duration) {
+	httpRequestsTotal.WithLabelValues(route, method, status).Inc()
+	httpRequestDuration.WithLabelValues(route, method).Observe(duration.Seconds())
+}
+
+func (Prometheus) ObserveRateLimitRejection(user string) {
+	rateLimitRejectionsTotal.WithLabelValues(bucketUser(user)).Inc()
+}
+
+// bucketUser folds a user ID into a fixed, small set of label values so
+// rate_limit_rejections_total can't accumulate one time series per user.
+func bucketUser(user string) string {
+	h := fnv.New32a()
+	h.Write([]byte(user))
+	return strconv.Itoa(int(h.Sum32() % rateLimitUserBuckets))
+}